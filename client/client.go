@@ -0,0 +1,51 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"subscription-service/internal/api"
+)
+
+// ListAllSubscriptions pages through GET /subscriptions until a
+// less-than-full page comes back, aggregating every subscription. perPage
+// controls the page size used while paging; it must be between 1 and 200,
+// the server's per_page maximum.
+func (c *Client) ListAllSubscriptions(userID, serviceName *string, perPage int) ([]api.Subscription, error) {
+	if perPage <= 0 || perPage > 200 {
+		return nil, fmt.Errorf("perPage должен быть от 1 до 200")
+	}
+
+	var all []api.Subscription
+	page := 1
+	for {
+		resp, err := c.ListSubscriptions(api.ListSubscriptionsParams{
+			UserId:      userID,
+			ServiceName: serviceName,
+			Page:        &page,
+			PerPage:     &perPage,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var subs []api.Subscription
+		err = func() error {
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("GET /subscriptions: неожиданный статус %d", resp.StatusCode)
+			}
+			return json.NewDecoder(resp.Body).Decode(&subs)
+		}()
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, subs...)
+		if len(subs) < perPage {
+			return all, nil
+		}
+		page++
+	}
+}
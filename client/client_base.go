@@ -0,0 +1,79 @@
+// Package client is a hand-maintained HTTP client for the API declared in
+// api/openapi.yaml. It is not produced by a code generator.
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"subscription-service/internal/api"
+)
+
+// Client is a thin HTTP client for the Subscription Service API described
+// by api/openapi.yaml.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient returns a Client that talks to baseURL, e.g.
+// "http://localhost:8080".
+func NewClient(baseURL string) *Client {
+	return &Client{baseURL: baseURL, httpClient: http.DefaultClient}
+}
+
+func (c *Client) CreateSubscription(req api.CreateSubscriptionRequest) (*http.Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.httpClient.Post(c.baseURL+"/subscriptions", "application/json", bytes.NewReader(body))
+}
+
+func (c *Client) GetSubscriptionById(id string) (*http.Response, error) {
+	return c.httpClient.Get(c.baseURL + "/subscriptions/" + id)
+}
+
+func (c *Client) UpdateSubscription(id string, req api.UpdateSubscriptionRequest) (*http.Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequest(http.MethodPut, c.baseURL+"/subscriptions/"+id, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	return c.httpClient.Do(httpReq)
+}
+
+func (c *Client) DeleteSubscription(id string) (*http.Response, error) {
+	httpReq, err := http.NewRequest(http.MethodDelete, c.baseURL+"/subscriptions/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.httpClient.Do(httpReq)
+}
+
+// ListSubscriptions fetches a single page, matching GET /subscriptions.
+func (c *Client) ListSubscriptions(params api.ListSubscriptionsParams) (*http.Response, error) {
+	query := url.Values{}
+	if params.UserId != nil {
+		query.Set("user_id", *params.UserId)
+	}
+	if params.ServiceName != nil {
+		query.Set("service_name", *params.ServiceName)
+	}
+	if params.Page != nil {
+		query.Set("page", strconv.Itoa(*params.Page))
+	}
+	if params.PerPage != nil {
+		query.Set("per_page", strconv.Itoa(*params.PerPage))
+	}
+
+	return c.httpClient.Get(fmt.Sprintf("%s/subscriptions?%s", c.baseURL, query.Encode()))
+}
@@ -15,8 +15,13 @@ import (
 	"os"
 	"time"
 
+	"subscription-service/internal/api"
+	"subscription-service/internal/billing"
 	"subscription-service/internal/config"
+	"subscription-service/internal/events"
 	"subscription-service/internal/handler"
+	"subscription-service/internal/middleware"
+	"subscription-service/internal/notifier"
 	"subscription-service/internal/repository"
 
 	"github.com/gorilla/mux"
@@ -46,17 +51,59 @@ func main() {
 	defer db.Close()
 
 	subRepo := repository.NewSubscriptionRepository(db)
-	subHandler := handler.NewHandler(subRepo)
+
+	stripeProvider := billing.NewStripeProvider(
+		os.Getenv("STRIPE_API_KEY"),
+		os.Getenv("STRIPE_WEBHOOK_SECRET"),
+		os.Getenv("STRIPE_SUCCESS_URL"),
+		os.Getenv("STRIPE_CANCEL_URL"),
+	)
+	billingSvc := billing.NewService(subRepo, stripeProvider)
+
+	notifierRepo := notifier.NewRepository(db)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go notifier.NewScanner(notifierRepo).Run(stop)
+	go notifier.NewExpiryScanner(subRepo, notifierRepo).Run(stop)
+
+	eventBus := events.NewBus()
+	if amqpURL := os.Getenv("AMQP_URL"); amqpURL != "" {
+		sink, err := events.NewAMQPSink(amqpURL, "goem.subscriptions")
+		if err != nil {
+			log.Printf("Не удалось подключить AMQP sink: %v", err)
+		} else {
+			eventBus.SetSink(sink)
+		}
+	}
+	eventRepo := events.NewSubscriptionRepository(subRepo, eventBus)
+
+	subHandler := handler.NewHandler(eventRepo, billingSvc, notifierRepo, eventBus)
+
+	validateRequest, err := middleware.ValidateRequest("api/openapi.yaml")
+	if err != nil {
+		log.Fatalf("Не удалось загрузить api/openapi.yaml: %v", err)
+	}
 
 	r := mux.NewRouter()
 	r.Use(handler.LogRequest)
+	r.Use(validateRequest)
+
+	// Routes outside api/openapi.yaml (billing, notifier, events, bulk) are
+	// registered directly before api.RegisterHandlers, since their literal
+	// /subscriptions/... paths would otherwise be shadowed by its
+	// "/subscriptions/{id}" wildcard.
+	r.HandleFunc("/events", subHandler.StreamEvents).Methods("GET")
+	r.HandleFunc("/subscriptions/bulk", subHandler.BulkImportSubscriptions).Methods("POST")
+	r.HandleFunc("/subscriptions/export", subHandler.ExportSubscriptions).Methods("GET")
+	r.HandleFunc("/subscriptions/{id}/checkout", subHandler.CreateCheckoutSession).Methods("POST")
+	r.HandleFunc("/webhooks/stripe", subHandler.StripeWebhook).Methods("POST")
+	r.HandleFunc("/notifier/subscribers", subHandler.CreateSubscriber).Methods("POST")
+	r.HandleFunc("/notifier/subscribers", subHandler.ListSubscribers).Methods("GET")
+	r.HandleFunc("/notifier/subscribers/{id}", subHandler.DeleteSubscriber).Methods("DELETE")
 
-	r.HandleFunc("/subscriptions", subHandler.CreateSubscription).Methods("POST")
-	r.HandleFunc("/subscriptions/total", subHandler.GetTotalCost).Methods("GET")
-	r.HandleFunc("/subscriptions", subHandler.ListSubscriptions).Methods("GET")
-	r.HandleFunc("/subscriptions/{id}", subHandler.GetSubscriptionByID).Methods("GET")
-	r.HandleFunc("/subscriptions/{id}", subHandler.UpdateSubscription).Methods("PUT")
-	r.HandleFunc("/subscriptions/{id}", subHandler.DeleteSubscription).Methods("DELETE")
+	// Core CRUD routes, as declared in api/openapi.yaml.
+	api.RegisterHandlers(r, subHandler)
 
 	port := os.Getenv("SERVER_PORT")
 	if port == "" {
@@ -0,0 +1,108 @@
+// Package api holds the server plumbing for the routes declared in
+// api/openapi.yaml. It is maintained by hand, not produced by a code
+// generator, and must be kept in sync with openapi.yaml and types.go
+// manually.
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// ServerInterfaceWrapper adapts ServerInterface methods to gorilla/mux's
+// http.HandlerFunc signature, decoding path and query parameters declared
+// in api/openapi.yaml.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+func (siw *ServerInterfaceWrapper) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	siw.Handler.CreateSubscription(w, r)
+}
+
+func (siw *ServerInterfaceWrapper) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	var params ListSubscriptionsParams
+
+	query := r.URL.Query()
+	if v := query.Get("user_id"); v != "" {
+		params.UserId = &v
+	}
+	if v := query.Get("service_name"); v != "" {
+		params.ServiceName = &v
+	}
+	if v := query.Get("page"); v != "" {
+		if page, err := strconv.Atoi(v); err == nil {
+			params.Page = &page
+		}
+	}
+	if v := query.Get("per_page"); v != "" {
+		if perPage, err := strconv.Atoi(v); err == nil {
+			params.PerPage = &perPage
+		}
+	}
+
+	siw.Handler.ListSubscriptions(w, r, params)
+}
+
+func (siw *ServerInterfaceWrapper) GetTotalCost(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	params := GetTotalCostParams{
+		StartMonth: query.Get("start_month"),
+		EndMonth:   query.Get("end_month"),
+	}
+	if v := query.Get("user_id"); v != "" {
+		params.UserId = &v
+	}
+	if v := query.Get("service_name"); v != "" {
+		params.ServiceName = &v
+	}
+
+	siw.Handler.GetTotalCost(w, r, params)
+}
+
+func (siw *ServerInterfaceWrapper) GetCostBreakdown(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	params := GetCostBreakdownParams{
+		StartMonth: query.Get("start_month"),
+		EndMonth:   query.Get("end_month"),
+	}
+	if v := query.Get("user_id"); v != "" {
+		params.UserId = &v
+	}
+	if v := query.Get("service_name"); v != "" {
+		params.ServiceName = &v
+	}
+	if v := query.Get("prorate"); v != "" {
+		prorate := v == "true"
+		params.Prorate = &prorate
+	}
+
+	siw.Handler.GetCostBreakdown(w, r, params)
+}
+
+func (siw *ServerInterfaceWrapper) GetSubscriptionById(w http.ResponseWriter, r *http.Request) {
+	siw.Handler.GetSubscriptionById(w, r, mux.Vars(r)["id"])
+}
+
+func (siw *ServerInterfaceWrapper) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	siw.Handler.UpdateSubscription(w, r, mux.Vars(r)["id"])
+}
+
+func (siw *ServerInterfaceWrapper) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	siw.Handler.DeleteSubscription(w, r, mux.Vars(r)["id"])
+}
+
+// RegisterHandlers wires every ServerInterface operation onto router.
+func RegisterHandlers(router *mux.Router, si ServerInterface) {
+	wrapper := ServerInterfaceWrapper{Handler: si}
+
+	router.HandleFunc("/subscriptions", wrapper.CreateSubscription).Methods(http.MethodPost)
+	router.HandleFunc("/subscriptions/total", wrapper.GetTotalCost).Methods(http.MethodGet)
+	router.HandleFunc("/subscriptions/cost", wrapper.GetCostBreakdown).Methods(http.MethodGet)
+	router.HandleFunc("/subscriptions", wrapper.ListSubscriptions).Methods(http.MethodGet)
+	router.HandleFunc("/subscriptions/{id}", wrapper.GetSubscriptionById).Methods(http.MethodGet)
+	router.HandleFunc("/subscriptions/{id}", wrapper.UpdateSubscription).Methods(http.MethodPut)
+	router.HandleFunc("/subscriptions/{id}", wrapper.DeleteSubscription).Methods(http.MethodDelete)
+}
@@ -0,0 +1,80 @@
+// Package api holds the request/response types and the ServerInterface
+// declared by api/openapi.yaml. It is hand-maintained alongside the spec,
+// not generated — keep the two in sync when the spec changes.
+package api
+
+import "net/http"
+
+// CreateSubscriptionRequest defines model for CreateSubscriptionRequest.
+type CreateSubscriptionRequest struct {
+	ServiceName string  `json:"service_name"`
+	Price       int     `json:"price"`
+	UserId      string  `json:"user_id"`
+	StartDate   string  `json:"start_date"`
+	EndDate     *string `json:"end_date,omitempty"`
+}
+
+// UpdateSubscriptionRequest defines model for UpdateSubscriptionRequest.
+type UpdateSubscriptionRequest = CreateSubscriptionRequest
+
+// Subscription defines model for Subscription.
+type Subscription struct {
+	Id          string  `json:"id"`
+	ServiceName string  `json:"service_name"`
+	Price       int     `json:"price"`
+	UserId      string  `json:"user_id"`
+	StartDate   string  `json:"start_date"`
+	EndDate     *string `json:"end_date,omitempty"`
+	Status      string  `json:"status"`
+}
+
+// ValidationError is the structured body returned by the validation
+// middleware when a request fails schema validation.
+type ValidationError struct {
+	Code    string `json:"code"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ListSubscriptionsParams defines parameters for ListSubscriptions.
+type ListSubscriptionsParams struct {
+	UserId      *string `json:"user_id,omitempty"`
+	ServiceName *string `json:"service_name,omitempty"`
+	Page        *int    `json:"page,omitempty"`
+	PerPage     *int    `json:"per_page,omitempty"`
+}
+
+// GetTotalCostParams defines parameters for GetTotalCost.
+type GetTotalCostParams struct {
+	StartMonth  string  `json:"start_month"`
+	EndMonth    string  `json:"end_month"`
+	UserId      *string `json:"user_id,omitempty"`
+	ServiceName *string `json:"service_name,omitempty"`
+}
+
+// GetCostBreakdownParams defines parameters for GetCostBreakdown.
+type GetCostBreakdownParams struct {
+	StartMonth  string  `json:"start_month"`
+	EndMonth    string  `json:"end_month"`
+	UserId      *string `json:"user_id,omitempty"`
+	ServiceName *string `json:"service_name,omitempty"`
+	Prorate     *bool   `json:"prorate,omitempty"`
+}
+
+// ServerInterface represents every operation declared in api/openapi.yaml.
+type ServerInterface interface {
+	// (POST /subscriptions)
+	CreateSubscription(w http.ResponseWriter, r *http.Request)
+	// (GET /subscriptions)
+	ListSubscriptions(w http.ResponseWriter, r *http.Request, params ListSubscriptionsParams)
+	// (GET /subscriptions/total)
+	GetTotalCost(w http.ResponseWriter, r *http.Request, params GetTotalCostParams)
+	// (GET /subscriptions/cost)
+	GetCostBreakdown(w http.ResponseWriter, r *http.Request, params GetCostBreakdownParams)
+	// (GET /subscriptions/{id})
+	GetSubscriptionById(w http.ResponseWriter, r *http.Request, id string)
+	// (PUT /subscriptions/{id})
+	UpdateSubscription(w http.ResponseWriter, r *http.Request, id string)
+	// (DELETE /subscriptions/{id})
+	DeleteSubscription(w http.ResponseWriter, r *http.Request, id string)
+}
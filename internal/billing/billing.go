@@ -0,0 +1,95 @@
+// Package billing integrates subscription records in this service with a
+// real payment provider, so that a Subscription's lifecycle (active,
+// past_due, canceled) reflects what has actually been billed.
+package billing
+
+import (
+	"fmt"
+	"log"
+
+	"subscription-service/internal/model"
+	"subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// PaymentProvider abstracts the external payment processor so the service
+// is not hard-wired to Stripe. StripeProvider is the only implementation
+// today, but CreateCheckoutSession/ConstructEvent are narrow enough to be
+// backed by another processor later without touching Service.
+type PaymentProvider interface {
+	// CreateCheckoutSession starts a hosted checkout for the given
+	// subscription and returns the URL the client should be redirected to.
+	CreateCheckoutSession(sub *model.Subscription) (url string, err error)
+
+	// ConstructEvent verifies the webhook signature on payload against the
+	// header and returns the decoded event on success.
+	ConstructEvent(payload []byte, signatureHeader string) (Event, error)
+}
+
+// Event is a provider webhook event, reduced to the fields Service needs to
+// mutate subscription state.
+type Event struct {
+	Type                 string
+	StripeSubscriptionID string
+	StripeCustomerID     string
+	LocalSubscriptionID  string // from checkout.session.completed metadata, only set for that event
+	Status               string
+	CurrentPeriodEnd     *string // MM-YYYY, matches model.Subscription.EndDate
+}
+
+// Supported webhook event types.
+const (
+	EventCheckoutSessionCompleted    = "checkout.session.completed"
+	EventInvoicePaid                 = "invoice.paid"
+	EventCustomerSubscriptionUpdate  = "customer.subscription.updated"
+	EventCustomerSubscriptionDeleted = "customer.subscription.deleted"
+)
+
+// Service wires a PaymentProvider to the subscription repository.
+type Service struct {
+	repo     *repository.SubscriptionRepository
+	provider PaymentProvider
+}
+
+func NewService(repo *repository.SubscriptionRepository, provider PaymentProvider) *Service {
+	return &Service{repo: repo, provider: provider}
+}
+
+// CreateCheckout starts a checkout session for sub and returns the URL to
+// redirect the client to.
+func (s *Service) CreateCheckout(sub *model.Subscription) (string, error) {
+	url, err := s.provider.CreateCheckoutSession(sub)
+	if err != nil {
+		log.Printf("Ошибка при создании checkout-сессии для подписки %s: %v", sub.ID, err)
+		return "", fmt.Errorf("не удалось создать checkout-сессию: %w", err)
+	}
+	return url, nil
+}
+
+// HandleWebhook verifies payload against signatureHeader and applies the
+// resulting event to the matching subscription.
+func (s *Service) HandleWebhook(payload []byte, signatureHeader string) error {
+	event, err := s.provider.ConstructEvent(payload, signatureHeader)
+	if err != nil {
+		return fmt.Errorf("не удалось проверить подпись webhook: %w", err)
+	}
+
+	switch event.Type {
+	case EventCheckoutSessionCompleted:
+		localID, err := uuid.Parse(event.LocalSubscriptionID)
+		if err != nil {
+			return fmt.Errorf("checkout.session.completed: некорректный subscription_id в metadata: %w", err)
+		}
+		return s.repo.UpdateStripeIDs(localID, event.StripeCustomerID, event.StripeSubscriptionID)
+	case EventInvoicePaid:
+		return s.repo.UpdateStatus(event.StripeSubscriptionID, model.StatusActive, nil)
+	case EventCustomerSubscriptionUpdate:
+		return s.repo.UpdateStatus(event.StripeSubscriptionID, event.Status, nil)
+	case EventCustomerSubscriptionDeleted:
+		return s.repo.UpdateStatus(event.StripeSubscriptionID, model.StatusCanceled, event.CurrentPeriodEnd)
+	default:
+		log.Printf("Игнорируем необрабатываемое событие Stripe: %s", event.Type)
+		return nil
+	}
+}
@@ -0,0 +1,134 @@
+package billing
+
+import (
+	"fmt"
+	"time"
+
+	"subscription-service/internal/model"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/checkout/session"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+// StripeProvider implements PaymentProvider against the real Stripe API.
+type StripeProvider struct {
+	webhookSecret string
+	successURL    string
+	cancelURL     string
+}
+
+// NewStripeProvider configures Stripe with apiKey and returns a provider
+// that verifies webhooks against webhookSecret. successURL/cancelURL are
+// the redirect targets after a hosted checkout session completes.
+func NewStripeProvider(apiKey, webhookSecret, successURL, cancelURL string) *StripeProvider {
+	stripe.Key = apiKey
+	return &StripeProvider{
+		webhookSecret: webhookSecret,
+		successURL:    successURL,
+		cancelURL:     cancelURL,
+	}
+}
+
+func (p *StripeProvider) CreateCheckoutSession(sub *model.Subscription) (string, error) {
+	params := &stripe.CheckoutSessionParams{
+		Mode:       stripe.String(string(stripe.CheckoutSessionModeSubscription)),
+		SuccessURL: stripe.String(p.successURL),
+		CancelURL:  stripe.String(p.cancelURL),
+		LineItems: []*stripe.CheckoutSessionLineItemParams{
+			{
+				Quantity: stripe.Int64(1),
+				PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
+					Currency:   stripe.String(string(stripe.CurrencyUSD)),
+					UnitAmount: stripe.Int64(int64(sub.Price)),
+					Product:    stripe.String(sub.ServiceName),
+					Recurring: &stripe.CheckoutSessionLineItemPriceDataRecurringParams{
+						Interval: stripe.String(string(stripe.PriceRecurringIntervalMonth)),
+					},
+				},
+			},
+		},
+	}
+	if sub.StripeCustomerID != nil {
+		params.Customer = stripe.String(*sub.StripeCustomerID)
+	}
+	params.AddMetadata("subscription_id", sub.ID.String())
+
+	sess, err := session.New(params)
+	if err != nil {
+		return "", fmt.Errorf("stripe: %w", err)
+	}
+	return sess.URL, nil
+}
+
+func (p *StripeProvider) ConstructEvent(payload []byte, signatureHeader string) (Event, error) {
+	stripeEvent, err := webhook.ConstructEvent(payload, signatureHeader, p.webhookSecret)
+	if err != nil {
+		return Event{}, fmt.Errorf("stripe: invalid signature: %w", err)
+	}
+
+	if string(stripeEvent.Type) == EventCheckoutSessionCompleted {
+		var cs stripe.CheckoutSession
+		if err := stripeEvent.Data.UnmarshalJSONValue("object", &cs); err != nil {
+			return Event{}, fmt.Errorf("stripe: unexpected event payload: %w", err)
+		}
+		event := Event{Type: string(stripeEvent.Type), LocalSubscriptionID: cs.Metadata["subscription_id"]}
+		if cs.Subscription != nil {
+			event.StripeSubscriptionID = cs.Subscription.ID
+		}
+		if cs.Customer != nil {
+			event.StripeCustomerID = cs.Customer.ID
+		}
+		return event, nil
+	}
+
+	if string(stripeEvent.Type) == EventInvoicePaid {
+		var inv stripe.Invoice
+		if err := stripeEvent.Data.UnmarshalJSONValue("object", &inv); err != nil {
+			return Event{}, fmt.Errorf("stripe: unexpected event payload: %w", err)
+		}
+		event := Event{Type: string(stripeEvent.Type)}
+		if inv.Subscription != nil {
+			event.StripeSubscriptionID = inv.Subscription.ID
+		}
+		if inv.Customer != nil {
+			event.StripeCustomerID = inv.Customer.ID
+		}
+		return event, nil
+	}
+
+	var sub stripe.Subscription
+	if err := stripeEvent.Data.UnmarshalJSONValue("object", &sub); err != nil {
+		return Event{}, fmt.Errorf("stripe: unexpected event payload: %w", err)
+	}
+
+	event := Event{
+		Type:                 string(stripeEvent.Type),
+		StripeSubscriptionID: sub.ID,
+		Status:               mapStatus(sub.Status),
+	}
+	if sub.CurrentPeriodEnd != 0 {
+		end := currentPeriodEndToMonthYear(sub.CurrentPeriodEnd)
+		event.CurrentPeriodEnd = &end
+	}
+	return event, nil
+}
+
+// currentPeriodEndToMonthYear converts a Stripe Unix timestamp to the
+// MM-YYYY format used by model.Subscription.EndDate.
+func currentPeriodEndToMonthYear(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format("01-2006")
+}
+
+func mapStatus(s stripe.SubscriptionStatus) string {
+	switch s {
+	case stripe.SubscriptionStatusActive, stripe.SubscriptionStatusTrialing:
+		return model.StatusActive
+	case stripe.SubscriptionStatusPastDue, stripe.SubscriptionStatusUnpaid:
+		return model.StatusPastDue
+	case stripe.SubscriptionStatusCanceled:
+		return model.StatusCanceled
+	default:
+		return string(s)
+	}
+}
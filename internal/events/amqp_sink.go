@@ -0,0 +1,46 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// AMQPSink publishes CloudEvents to a RabbitMQ exchange. It implements Sink.
+type AMQPSink struct {
+	channel  *amqp.Channel
+	exchange string
+}
+
+// NewAMQPSink dials amqpURL and declares a topic exchange named exchange,
+// returning a Sink ready to publish CloudEvents to it.
+func NewAMQPSink(amqpURL, exchange string) (*AMQPSink, error) {
+	conn, err := amqp.Dial(amqpURL)
+	if err != nil {
+		return nil, fmt.Errorf("amqp: не удалось подключиться: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("amqp: не удалось открыть канал: %w", err)
+	}
+
+	if err := ch.ExchangeDeclare(exchange, "topic", true, false, false, false, nil); err != nil {
+		return nil, fmt.Errorf("amqp: не удалось объявить exchange %s: %w", exchange, err)
+	}
+
+	return &AMQPSink{channel: ch, exchange: exchange}, nil
+}
+
+func (s *AMQPSink) Send(event CloudEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("amqp: не удалось сериализовать событие: %w", err)
+	}
+
+	return s.channel.Publish(s.exchange, event.Type, false, false, amqp.Publishing{
+		ContentType: "application/cloudevents+json",
+		Body:        body,
+	})
+}
@@ -0,0 +1,82 @@
+package events
+
+import (
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Sink forwards every published CloudEvent to an external system, e.g. an
+// AMQP or Kafka topic. Publish calls Send from its own goroutine and does
+// not wait for it to return.
+type Sink interface {
+	Send(event CloudEvent) error
+}
+
+// Bus fans out published CloudEvents to SSE subscribers and, if configured,
+// to a Sink.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[uuid.UUID]chan CloudEvent
+	sink Sink
+}
+
+func NewBus() *Bus {
+	return &Bus{subs: make(map[uuid.UUID]chan CloudEvent)}
+}
+
+// SetSink configures an optional downstream sink (AMQP/Kafka). Passing nil
+// disables it.
+func (b *Bus) SetSink(sink Sink) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sink = sink
+}
+
+// Subscribe registers a new SSE listener and returns its channel plus an
+// unsubscribe func the caller must invoke when the client disconnects.
+func (b *Bus) Subscribe() (<-chan CloudEvent, func()) {
+	id := uuid.New()
+	ch := make(chan CloudEvent, 16)
+
+	b.mu.Lock()
+	b.subs[id] = ch
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if ch, ok := b.subs[id]; ok {
+			close(ch)
+			delete(b.subs, id)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// Publish fans event out to every SSE subscriber and the configured sink.
+// Slow subscribers have the event dropped rather than blocking Publish.
+// The sink send happens in its own goroutine, not under the bus lock, so a
+// slow or unreachable broker can't add latency to the Create/Update/Delete
+// request that triggered the publish.
+func (b *Bus) Publish(event CloudEvent) {
+	b.mu.RLock()
+	sink := b.sink
+	for _, ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("Подписчик SSE отстаёт, событие %s пропущено", event.ID)
+		}
+	}
+	b.mu.RUnlock()
+
+	if sink != nil {
+		go func() {
+			if err := sink.Send(event); err != nil {
+				log.Printf("Ошибка при отправке события %s во внешний sink: %v", event.ID, err)
+			}
+		}()
+	}
+}
@@ -0,0 +1,69 @@
+package events
+
+import (
+	"subscription-service/internal/model"
+	"subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionRepository decorates repository.SubscriptionRepository,
+// publishing a CloudEvent to bus only after the wrapped Create/Update/
+// Delete call has committed successfully. All other methods (GetByID,
+// List, ...) are promoted from the embedded repository unchanged.
+type SubscriptionRepository struct {
+	*repository.SubscriptionRepository
+	bus *Bus
+}
+
+func NewSubscriptionRepository(repo *repository.SubscriptionRepository, bus *Bus) *SubscriptionRepository {
+	return &SubscriptionRepository{SubscriptionRepository: repo, bus: bus}
+}
+
+func (r *SubscriptionRepository) Create(sub *model.Subscription) error {
+	if err := r.SubscriptionRepository.Create(sub); err != nil {
+		return err
+	}
+	r.bus.Publish(NewSubscriptionEvent(TypeSubscriptionCreated, sub))
+	return nil
+}
+
+// BulkCreate wraps repository.SubscriptionRepository.BulkCreate, publishing
+// a creation CloudEvent for each row once the whole batch has committed.
+// BulkCreate only returns a nil error when every row was inserted, so
+// publishing unconditionally here never announces a row that was rolled
+// back.
+func (r *SubscriptionRepository) BulkCreate(subs []model.Subscription) ([]repository.BulkImportError, error) {
+	importErrors, err := r.SubscriptionRepository.BulkCreate(subs)
+	if err != nil {
+		return importErrors, err
+	}
+	for i := range subs {
+		r.bus.Publish(NewSubscriptionEvent(TypeSubscriptionCreated, &subs[i]))
+	}
+	return importErrors, nil
+}
+
+func (r *SubscriptionRepository) Update(sub *model.Subscription) error {
+	if err := r.SubscriptionRepository.Update(sub); err != nil {
+		return err
+	}
+	r.bus.Publish(NewSubscriptionEvent(TypeSubscriptionUpdated, sub))
+	return nil
+}
+
+func (r *SubscriptionRepository) Delete(id uuid.UUID) error {
+	sub, getErr := r.SubscriptionRepository.GetByID(id)
+
+	if err := r.SubscriptionRepository.Delete(id); err != nil {
+		return err
+	}
+
+	if getErr != nil {
+		// The subscription was deleted successfully but we no longer have
+		// its fields; still emit the event, identified by ID alone.
+		sub = &model.Subscription{ID: id}
+	}
+	r.bus.Publish(NewSubscriptionEvent(TypeSubscriptionDeleted, sub))
+	return nil
+}
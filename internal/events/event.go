@@ -0,0 +1,56 @@
+// Package events emits CloudEvents 1.0 envelopes whenever a subscription is
+// created, updated, or deleted, and exposes them to consumers over an SSE
+// stream (and, optionally, an AMQP/Kafka sink).
+package events
+
+import (
+	"time"
+
+	"subscription-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// CloudEvents type values, following the com.goem.<resource>.<verb> scheme.
+const (
+	TypeSubscriptionCreated = "com.goem.subscription.created"
+	TypeSubscriptionUpdated = "com.goem.subscription.updated"
+	TypeSubscriptionDeleted = "com.goem.subscription.deleted"
+)
+
+const source = "/subscription-service"
+
+// CloudEvent is a CloudEvents 1.0 JSON envelope.
+// See https://github.com/cloudevents/spec/blob/v1.0.2/cloudevents/formats/json-format.md
+type CloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	Type            string      `json:"type"`
+	Source          string      `json:"source"`
+	ID              string      `json:"id"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            interface{} `json:"data"`
+}
+
+// SubscriptionData is the envelope payload for subscription events.
+type SubscriptionData struct {
+	Subscription *model.Subscription `json:"subscription"`
+	UserID       uuid.UUID           `json:"user_id"`
+}
+
+// NewSubscriptionEvent builds a CloudEvent for a subscription lifecycle
+// change of the given eventType.
+func NewSubscriptionEvent(eventType string, sub *model.Subscription) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     "1.0",
+		Type:            eventType,
+		Source:          source,
+		ID:              uuid.NewString(),
+		Time:            time.Now(),
+		DataContentType: "application/json",
+		Data: SubscriptionData{
+			Subscription: sub,
+			UserID:       sub.UserID,
+		},
+	}
+}
@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"subscription-service/internal/model"
+	"subscription-service/internal/notifier"
+	"subscription-service/internal/repository"
+
+	"git.sr.ht/~sircmpwn/go-bare"
+	"github.com/google/uuid"
+)
+
+// bulkImportRow is the shape accepted by BulkImportSubscriptions, decoded
+// from either a JSON array or a CSV body.
+type bulkImportRow struct {
+	ServiceName string  `json:"service_name"`
+	Price       int     `json:"price"`
+	UserID      string  `json:"user_id"`
+	StartDate   string  `json:"start_date"`
+	EndDate     *string `json:"end_date,omitempty"`
+}
+
+// bulkRow mirrors model.Subscription for the BARE binary encoding used by
+// /subscriptions/export?format=bare. go-bare maps a *string to an
+// optional field, matching EndDate's "optional" semantics in JSON.
+type bulkRow struct {
+	ID          string  `bare:"id"`
+	ServiceName string  `bare:"service_name"`
+	Price       int64   `bare:"price"`
+	UserID      string  `bare:"user_id"`
+	StartDate   string  `bare:"start_date"`
+	EndDate     *string `bare:"end_date"`
+	Status      string  `bare:"status"`
+}
+
+func toBulkRow(sub model.Subscription) bulkRow {
+	return bulkRow{
+		ID:          sub.ID.String(),
+		ServiceName: sub.ServiceName,
+		Price:       int64(sub.Price),
+		UserID:      sub.UserID.String(),
+		StartDate:   sub.StartDate,
+		EndDate:     sub.EndDate,
+		Status:      sub.Status,
+	}
+}
+
+// BulkImportSubscriptions imports many subscriptions in one transactional
+// request, accepting either a JSON array or a CSV body.
+//
+//	@Summary		Bulk import subscriptions
+//	@Description	Import many subscriptions at once from a JSON array or text/csv body; the whole batch is rejected if any row is invalid
+//	@Tags			subscriptions
+//	@Accept			json,text/csv
+//	@Produce		json
+//	@Success		201	{object}	object{imported=int}
+//	@Failure		400	{object}	object{imported=int,errors=[]repository.BulkImportError}
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/subscriptions/bulk [post]
+func (h *Handler) BulkImportSubscriptions(w http.ResponseWriter, r *http.Request) {
+	var rows []bulkImportRow
+
+	if strings.Contains(r.Header.Get("Content-Type"), "text/csv") {
+		if err := parseBulkCSV(r.Body, &rows); err != nil {
+			http.Error(w, "Некорректный CSV: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		if err := json.NewDecoder(r.Body).Decode(&rows); err != nil {
+			http.Error(w, "Некорректное тело запроса", http.StatusBadRequest)
+			return
+		}
+	}
+
+	subs := make([]model.Subscription, 0, len(rows))
+	var rowErrors []repository.BulkImportError
+	for i, row := range rows {
+		if row.ServiceName == "" {
+			rowErrors = append(rowErrors, repository.BulkImportError{Row: i, Message: "service_name не может быть пустым"})
+			continue
+		}
+		if row.Price <= 0 {
+			rowErrors = append(rowErrors, repository.BulkImportError{Row: i, Message: "price должен быть положительным целым числом"})
+			continue
+		}
+		userID, err := uuid.Parse(row.UserID)
+		if err != nil {
+			rowErrors = append(rowErrors, repository.BulkImportError{Row: i, Message: "user_id должен быть валидным UUID"})
+			continue
+		}
+
+		subs = append(subs, model.Subscription{
+			ID:          uuid.New(),
+			ServiceName: row.ServiceName,
+			Price:       row.Price,
+			UserID:      userID,
+			StartDate:   row.StartDate,
+			EndDate:     row.EndDate,
+			Status:      model.StatusActive,
+		})
+	}
+
+	// Report handler-level validation failures through the same
+	// {imported, errors} shape BulkCreate uses for its own (start_date/
+	// end_date) validation, so a client sees one consistent error report
+	// regardless of which check rejected a row.
+	if len(rowErrors) > 0 {
+		SendJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"imported": 0,
+			"errors":   rowErrors,
+		})
+		return
+	}
+
+	importErrors, err := h.SubscriptionRepo.BulkCreate(subs)
+	if err != nil {
+		SendJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"imported": 0,
+			"errors":   importErrors,
+		})
+		return
+	}
+
+	for _, sub := range subs {
+		if err := h.NotifierRepo.Enqueue(notifier.EventSubscriptionCreated, notifier.Payload{
+			EventType:      notifier.EventSubscriptionCreated,
+			SubscriptionID: sub.ID,
+			OccurredAt:     time.Now(),
+		}); err != nil {
+			log.Printf("Ошибка при постановке события создания подписки %s в очередь: %v", sub.ID, err)
+		}
+	}
+
+	SendJSON(w, http.StatusCreated, map[string]int{"imported": len(subs)})
+}
+
+// parseBulkCSV reads a CSV body with the header
+// "service_name,price,user_id,start_date,end_date" into rows.
+func parseBulkCSV(body io.Reader, rows *[]bulkImportRow) error {
+	reader := csv.NewReader(body)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	for _, record := range records[1:] { // skip header
+		if len(record) < 4 {
+			return fmt.Errorf("ожидается минимум 4 столбца: service_name,price,user_id,start_date[,end_date]")
+		}
+
+		price, err := strconv.Atoi(record[1])
+		if err != nil {
+			return fmt.Errorf("некорректное значение price: %s", record[1])
+		}
+
+		row := bulkImportRow{
+			ServiceName: record[0],
+			Price:       price,
+			UserID:      record[2],
+			StartDate:   record[3],
+		}
+		if len(record) > 4 && record[4] != "" {
+			endDate := record[4]
+			row.EndDate = &endDate
+		}
+		*rows = append(*rows, row)
+	}
+	return nil
+}
+
+// ExportSubscriptions streams every matching subscription as CSV, JSON, or
+// the compact BARE binary encoding.
+//
+//	@Summary		Export subscriptions
+//	@Description	Stream all subscriptions, optionally filtered by user_id or service_name, as csv, json, or bare
+//	@Tags			subscriptions
+//	@Produce		json,text/csv,application/octet-stream
+//	@Param			format			query		string	false	"csv, json, or bare (default json)"
+//	@Param			user_id			query		string	false	"Filter by user ID"
+//	@Param			service_name	query		string	false	"Filter by service name"
+//	@Success		200	{string}	string
+//	@Failure		400	{string}	string	"Bad request"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/subscriptions/export [get]
+func (h *Handler) ExportSubscriptions(w http.ResponseWriter, r *http.Request) {
+	userIDStr := r.URL.Query().Get("user_id")
+	serviceName := r.URL.Query().Get("service_name")
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+
+	var userID *uuid.UUID
+	if userIDStr != "" {
+		id, err := uuid.Parse(userIDStr)
+		if err != nil {
+			http.Error(w, "user_id должен быть валидным UUID", http.StatusBadRequest)
+			return
+		}
+		userID = &id
+	}
+	var serviceNamePtr *string
+	if serviceName != "" {
+		serviceNamePtr = &serviceName
+	}
+
+	subs, err := h.SubscriptionRepo.List(userID, serviceNamePtr, 0, 0)
+	if err != nil {
+		http.Error(w, "Ошибка при получении списка подписок", http.StatusInternalServerError)
+		return
+	}
+
+	switch format {
+	case "csv":
+		exportCSV(w, subs)
+	case "bare":
+		exportBARE(w, subs)
+	case "json":
+		SendJSON(w, http.StatusOK, subs)
+	default:
+		http.Error(w, "Неизвестный формат: допустимы csv, json, bare", http.StatusBadRequest)
+	}
+}
+
+func exportCSV(w http.ResponseWriter, subs []model.Subscription) {
+	w.Header().Set("Content-Type", "text/csv")
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	writer.Write([]string{"id", "service_name", "price", "user_id", "start_date", "end_date", "status"})
+	for _, sub := range subs {
+		endDate := ""
+		if sub.EndDate != nil {
+			endDate = *sub.EndDate
+		}
+		writer.Write([]string{
+			sub.ID.String(),
+			sub.ServiceName,
+			strconv.Itoa(sub.Price),
+			sub.UserID.String(),
+			sub.StartDate,
+			endDate,
+			sub.Status,
+		})
+	}
+}
+
+func exportBARE(w http.ResponseWriter, subs []model.Subscription) {
+	w.Header().Set("Content-Type", "application/octet-stream")
+
+	rows := make([]bulkRow, len(subs))
+	for i, sub := range subs {
+		rows[i] = toBulkRow(sub)
+	}
+
+	if err := bare.MarshalWriter(w, &rows); err != nil {
+		http.Error(w, "Не удалось закодировать ответ в BARE", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"subscription-service/internal/events"
+)
+
+// StreamEvents streams subscription CloudEvents over Server-Sent Events.
+//
+//	@Summary		Stream subscription events
+//	@Description	Subscribe to a live SSE stream of CloudEvents for subscription changes, optionally filtered by type and/or user_id
+//	@Tags			events
+//	@Produce		text/event-stream
+//	@Param			type		query	string	false	"CloudEvents type to filter on, e.g. com.goem.subscription.created"
+//	@Param			user_id		query	string	false	"Filter events to a single user"
+//	@Success		200	{string}	string
+//	@Router			/events [get]
+func (h *Handler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Потоковая передача не поддерживается", http.StatusInternalServerError)
+		return
+	}
+
+	// The server sets a write deadline for ordinary request/response
+	// handling; an SSE stream has to outlive it, so disable it here rather
+	// than letting /events get force-closed every WriteTimeout.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("Не удалось снять write deadline для потока событий: %v", err)
+	}
+
+	typeFilter := r.URL.Query().Get("type")
+	userIDFilter := r.URL.Query().Get("user_id")
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	stream, unsubscribe := h.EventBus.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-stream:
+			if !ok {
+				return
+			}
+			if !matchesFilters(event, typeFilter, userIDFilter) {
+				continue
+			}
+
+			payload, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", payload)
+			flusher.Flush()
+		}
+	}
+}
+
+func matchesFilters(event events.CloudEvent, typeFilter, userIDFilter string) bool {
+	if typeFilter != "" && event.Type != typeFilter {
+		return false
+	}
+	if userIDFilter != "" {
+		data, ok := event.Data.(events.SubscriptionData)
+		if !ok || data.UserID.String() != userIDFilter {
+			return false
+		}
+	}
+	return true
+}
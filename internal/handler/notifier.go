@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"subscription-service/internal/notifier"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// CreateSubscriber registers a webhook subscriber.
+//
+//	@Summary		Register a webhook subscriber
+//	@Description	Register a URL to receive signed callbacks for subscription events
+//	@Tags			notifier
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		CreateSubscriberRequest	true	"Subscriber data"
+//	@Success		201	{object}	notifier.Subscriber
+//	@Failure		400	{string}	string	"Bad request"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/notifier/subscribers [post]
+func (h *Handler) CreateSubscriber(w http.ResponseWriter, r *http.Request) {
+	var input CreateSubscriberRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Некорректное тело запроса", http.StatusBadRequest)
+		return
+	}
+
+	if input.URL == "" {
+		http.Error(w, "url не может быть пустым", http.StatusBadRequest)
+		return
+	}
+	if input.Secret == "" {
+		http.Error(w, "secret не может быть пустым", http.StatusBadRequest)
+		return
+	}
+	if input.EventType == "" {
+		input.EventType = "*"
+	}
+
+	sub := &notifier.Subscriber{
+		ID:        uuid.New(),
+		URL:       input.URL,
+		EventType: input.EventType,
+		Secret:    input.Secret,
+		CreatedAt: time.Now(),
+	}
+
+	if err := h.NotifierRepo.CreateSubscriber(sub); err != nil {
+		http.Error(w, "Не удалось зарегистрировать подписчика", http.StatusInternalServerError)
+		return
+	}
+
+	SendJSON(w, http.StatusCreated, sub)
+}
+
+// ListSubscribers lists registered webhook subscribers.
+//
+//	@Summary		List webhook subscribers
+//	@Description	Get every registered notifier subscriber
+//	@Tags			notifier
+//	@Produce		json
+//	@Success		200	{array}		notifier.Subscriber
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/notifier/subscribers [get]
+func (h *Handler) ListSubscribers(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.NotifierRepo.ListSubscribers()
+	if err != nil {
+		http.Error(w, "Ошибка при получении списка подписчиков", http.StatusInternalServerError)
+		return
+	}
+	SendJSON(w, http.StatusOK, subs)
+}
+
+// DeleteSubscriber removes a webhook subscriber by ID.
+//
+//	@Summary		Delete a webhook subscriber
+//	@Description	Stop delivering events to a registered subscriber
+//	@Tags			notifier
+//	@Param			id	path		string	true	"Subscriber ID"
+//	@Success		204	{string}	string
+//	@Failure		400	{string}	string	"Invalid ID format"
+//	@Failure		404	{string}	string	"Subscriber not found"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/notifier/subscribers/{id} [delete]
+func (h *Handler) DeleteSubscriber(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	idStr := vars["id"]
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "Некорректный ID подписчика", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.NotifierRepo.DeleteSubscriber(id); err != nil {
+		http.Error(w, "Подписчик не найден", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateSubscriberRequest represents the request body for registering a
+// notifier subscriber.
+//
+//	@Description	Notifier subscriber registration request
+type CreateSubscriberRequest struct {
+	URL       string `json:"url" example:"https://example.com/webhooks/goem"`
+	EventType string `json:"event_type,omitempty" example:"subscription.expiring.t_minus_7"`
+	Secret    string `json:"secret" example:"whsec_example"`
+}
@@ -3,10 +3,15 @@ package handler
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"subscription-service/internal/api"
+	"subscription-service/internal/billing"
+	"subscription-service/internal/events"
 	"subscription-service/internal/model"
-	"subscription-service/internal/repository"
+	"subscription-service/internal/notifier"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,13 +19,19 @@ import (
 )
 
 type Handler struct {
-	SubscriptionRepo *repository.SubscriptionRepository
+	SubscriptionRepo *events.SubscriptionRepository
+	Billing          *billing.Service
+	NotifierRepo     *notifier.Repository
+	EventBus         *events.Bus
 }
 
-func NewHandler(repo *repository.SubscriptionRepository) *Handler {
-	return &Handler{SubscriptionRepo: repo}
+func NewHandler(repo *events.SubscriptionRepository, billingSvc *billing.Service, notifierRepo *notifier.Repository, bus *events.Bus) *Handler {
+	return &Handler{SubscriptionRepo: repo, Billing: billingSvc, NotifierRepo: notifierRepo, EventBus: bus}
 }
 
+// Handler implements api.ServerInterface, declared by api/openapi.yaml.
+var _ api.ServerInterface = (*Handler)(nil)
+
 func SendJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -37,53 +48,34 @@ func LogRequest(next http.Handler) http.Handler {
 	})
 }
 
-// CreateSubscription creates a new subscription.
+// CreateSubscription creates a new subscription. Request validation
+// (service_name non-empty, price positive, user_id a UUID, start_date in
+// MM-YYYY format) is enforced by the OpenAPI validation middleware before
+// this handler ever sees the request.
 //
 //	@Summary		Create a subscription
 //	@Description	Create a new user subscription record
 //	@Tags			subscriptions
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		CreateSubscriptionRequest	true	"Subscription data"
+//	@Param			request	body		api.CreateSubscriptionRequest	true	"Subscription data"
 //	@Success		201	{object}	model.Subscription
-//	@Failure		400	{string}	string	"Bad request"
+//	@Failure		400	{object}	api.ValidationError
 //	@Failure		500	{string}	string	"Internal server error"
 //	@Router			/subscriptions [post]
 func (h *Handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
-	var input struct {
-		ServiceName string  `json:"service_name"`
-		Price       int     `json:"price"`
-		UserID      string  `json:"user_id"`
-		StartDate   string  `json:"start_date"`
-		EndDate     *string `json:"end_date,omitempty"`
-	}
-
+	var input api.CreateSubscriptionRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		http.Error(w, "Некорректное тело запроса", http.StatusBadRequest)
 		return
 	}
 
-	if input.ServiceName == "" {
-		http.Error(w, "service_name не может быть пустым", http.StatusBadRequest)
-		return
-	}
-
-	if input.Price <= 0 {
-		http.Error(w, "price должен быть положительным целым числом", http.StatusBadRequest)
-		return
-	}
-
-	userID, err := uuid.Parse(input.UserID)
+	userID, err := uuid.Parse(input.UserId)
 	if err != nil {
 		http.Error(w, "user_id должен быть валидным UUID", http.StatusBadRequest)
 		return
 	}
 
-	if !isValidMonthYear(input.StartDate) {
-		http.Error(w, "start_date должен быть в формате MM-YYYY (например, 07-2025)", http.StatusBadRequest)
-		return
-	}
-
 	sub := &model.Subscription{
 		ID:          uuid.New(),
 		ServiceName: input.ServiceName,
@@ -91,6 +83,7 @@ func (h *Handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
 		UserID:      userID,
 		StartDate:   input.StartDate,
 		EndDate:     input.EndDate,
+		Status:      model.StatusActive,
 	}
 
 	if err := h.SubscriptionRepo.Create(sub); err != nil {
@@ -104,10 +97,18 @@ func (h *Handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.NotifierRepo.Enqueue(notifier.EventSubscriptionCreated, notifier.Payload{
+		EventType:      notifier.EventSubscriptionCreated,
+		SubscriptionID: saved.ID,
+		OccurredAt:     time.Now(),
+	}); err != nil {
+		log.Printf("Ошибка при постановке события создания подписки в очередь: %v", err)
+	}
+
 	SendJSON(w, http.StatusCreated, saved)
 }
 
-// GetSubscriptionByID retrieves a subscription by ID.
+// GetSubscriptionById retrieves a subscription by ID.
 //
 //	@Summary		Get subscription by ID
 //	@Description	Get a single subscription by its UUID
@@ -119,10 +120,7 @@ func (h *Handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
 //	@Failure		404	{string}	string	"Subscription not found"
 //	@Failure		500	{string}	string	"Internal server error"
 //	@Router			/subscriptions/{id} [get]
-func (h *Handler) GetSubscriptionByID(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	idStr := vars["id"]
-
+func (h *Handler) GetSubscriptionById(w http.ResponseWriter, r *http.Request, idStr string) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		http.Error(w, "Некорректный ID подписки", http.StatusBadRequest)
@@ -138,25 +136,35 @@ func (h *Handler) GetSubscriptionByID(w http.ResponseWriter, r *http.Request) {
 	SendJSON(w, http.StatusOK, sub)
 }
 
-// ListSubscriptions lists all subscriptions with optional filters.
+// defaultPerPage / maxPerPage bound ListSubscriptions pagination.
+const (
+	defaultPerPage = 50
+	maxPerPage     = 200
+)
+
+// ListSubscriptions lists subscriptions with optional filters, paginated
+// via ?page=/?per_page= (used by the client's ListAllSubscriptions
+// helper). This replaced the old unpaginated behavior: a request with no
+// ?per_page now returns at most defaultPerPage rows, not every match. The
+// X-Total-Count response header carries the full match count so a caller
+// that doesn't page can tell its response was truncated.
 //
 //	@Summary		List subscriptions
-//	@Description	Get a list of subscriptions, optionally filtered by user_id or service_name
+//	@Description	Get a page of subscriptions, optionally filtered by user_id or service_name; total match count is reported in the X-Total-Count header
 //	@Tags			subscriptions
 //	@Produce		json
 //	@Param			user_id			query		string	false	"Filter by user ID"
 //	@Param			service_name	query		string	false	"Filter by service name"
-//	@Success		200	{array}		model.Subscription
+//	@Param			page			query		int		false	"1-indexed page number (default 1)"
+//	@Param			per_page		query		int		false	"Page size, max 200 (default 50)"
+//	@Success		200	{array}		model.Subscription	"Header X-Total-Count reports the total match count"
 //	@Failure		400	{string}	string	"Bad request"
 //	@Failure		500	{string}	string	"Internal server error"
 //	@Router			/subscriptions [get]
-func (h *Handler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
-	userIDStr := r.URL.Query().Get("user_id")
-	serviceName := r.URL.Query().Get("service_name")
-
+func (h *Handler) ListSubscriptions(w http.ResponseWriter, r *http.Request, params api.ListSubscriptionsParams) {
 	var userID *uuid.UUID
-	if userIDStr != "" {
-		id, err := uuid.Parse(userIDStr)
+	if params.UserId != nil {
+		id, err := uuid.Parse(*params.UserId)
 		if err != nil {
 			http.Error(w, "user_id должен быть валидным UUID", http.StatusBadRequest)
 			return
@@ -164,17 +172,28 @@ func (h *Handler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
 		userID = &id
 	}
 
-	var serviceNamePtr *string
-	if serviceName != "" {
-		serviceNamePtr = &serviceName
+	page := 1
+	if params.Page != nil && *params.Page > 0 {
+		page = *params.Page
+	}
+	perPage := defaultPerPage
+	if params.PerPage != nil && *params.PerPage > 0 && *params.PerPage <= maxPerPage {
+		perPage = *params.PerPage
+	}
+
+	total, err := h.SubscriptionRepo.Count(userID, params.ServiceName)
+	if err != nil {
+		http.Error(w, "Ошибка при подсчёте подписок", http.StatusInternalServerError)
+		return
 	}
 
-	subs, err := h.SubscriptionRepo.List(userID, serviceNamePtr)
+	subs, err := h.SubscriptionRepo.List(userID, params.ServiceName, perPage, (page-1)*perPage)
 	if err != nil {
 		http.Error(w, "Ошибка при получении списка подписок", http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
 	SendJSON(w, http.StatusOK, subs)
 }
 
@@ -192,21 +211,45 @@ func (h *Handler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
 //	@Failure		400	{string}	string	"Bad request"
 //	@Failure		500	{string}	string	"Internal server error"
 //	@Router			/subscriptions/total [get]
-func (h *Handler) GetTotalCost(w http.ResponseWriter, r *http.Request) {
-	startMonth := r.URL.Query().Get("start_month")
-	endMonth := r.URL.Query().Get("end_month")
+func (h *Handler) GetTotalCost(w http.ResponseWriter, r *http.Request, params api.GetTotalCostParams) {
+	var userID *uuid.UUID
+	if params.UserId != nil {
+		id, err := uuid.Parse(*params.UserId)
+		if err != nil {
+			http.Error(w, "user_id должен быть валидным UUID", http.StatusBadRequest)
+			return
+		}
+		userID = &id
+	}
 
-	if startMonth == "" || endMonth == "" {
-		http.Error(w, "Параметры start_month и end_month обязательны", http.StatusBadRequest)
+	total, err := h.SubscriptionRepo.GetTotalCost(params.StartMonth, params.EndMonth, userID, params.ServiceName)
+	if err != nil {
+		http.Error(w, "Ошибка при расчёте суммы", http.StatusInternalServerError)
 		return
 	}
 
-	userIDStr := r.URL.Query().Get("user_id")
-	serviceName := r.URL.Query().Get("service_name")
+	SendJSON(w, http.StatusOK, map[string]int{"total": total})
+}
 
+// GetCostBreakdown returns a per-month cost breakdown for a period.
+//
+//	@Summary		Get cost breakdown
+//	@Description	Compute a per-month cost breakdown for a given period, with optional user_id/service_name filters and prorate flagging of partial months
+//	@Tags			subscriptions
+//	@Produce		json
+//	@Param			start_month		query		string	true	"Start month in MM-YYYY format"
+//	@Param			end_month		query		string	true	"End month in MM-YYYY format"
+//	@Param			user_id			query		string	false	"Filter by user ID"
+//	@Param			service_name	query		string	false	"Filter by service name"
+//	@Param			prorate			query		bool	false	"Flag months where a subscription started or ended mid-window"
+//	@Success		200	{array}		repository.MonthCost
+//	@Failure		400	{string}	string	"Bad request"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/subscriptions/cost [get]
+func (h *Handler) GetCostBreakdown(w http.ResponseWriter, r *http.Request, params api.GetCostBreakdownParams) {
 	var userID *uuid.UUID
-	if userIDStr != "" {
-		id, err := uuid.Parse(userIDStr)
+	if params.UserId != nil {
+		id, err := uuid.Parse(*params.UserId)
 		if err != nil {
 			http.Error(w, "user_id должен быть валидным UUID", http.StatusBadRequest)
 			return
@@ -214,21 +257,20 @@ func (h *Handler) GetTotalCost(w http.ResponseWriter, r *http.Request) {
 		userID = &id
 	}
 
-	var serviceNamePtr *string
-	if serviceName != "" {
-		serviceNamePtr = &serviceName
-	}
+	prorate := params.Prorate != nil && *params.Prorate
 
-	total, err := h.SubscriptionRepo.GetTotalCost(startMonth, endMonth, userID, serviceNamePtr)
+	months, err := h.SubscriptionRepo.CostBreakdown(params.StartMonth, params.EndMonth, userID, params.ServiceName, prorate)
 	if err != nil {
-		http.Error(w, "Ошибка при расчёте суммы", http.StatusInternalServerError)
+		http.Error(w, "Ошибка при расчёте помесячной стоимости", http.StatusInternalServerError)
 		return
 	}
 
-	SendJSON(w, http.StatusOK, map[string]int{"total": total})
+	SendJSON(w, http.StatusOK, months)
 }
 
-// UpdateSubscription updates an existing subscription.
+// UpdateSubscription updates an existing subscription. As with
+// CreateSubscription, field validation is handled by the OpenAPI
+// validation middleware ahead of this handler.
 //
 //	@Summary		Update subscription
 //	@Description	Update an existing subscription by ID
@@ -236,56 +278,31 @@ func (h *Handler) GetTotalCost(w http.ResponseWriter, r *http.Request) {
 //	@Accept			json
 //	@Produce		json
 //	@Param			id				path		string	true	"Subscription ID"
-//	@Param			request			body		CreateSubscriptionRequest	true	"Updated subscription data"
+//	@Param			request			body		api.UpdateSubscriptionRequest	true	"Updated subscription data"
 //	@Success		200	{object}	model.Subscription
-//	@Failure		400	{string}	string	"Bad request"
+//	@Failure		400	{object}	api.ValidationError
 //	@Failure		404	{string}	string	"Subscription not found"
 //	@Failure		500	{string}	string	"Internal server error"
 //	@Router			/subscriptions/{id} [put]
-func (h *Handler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	idStr := vars["id"]
-
+func (h *Handler) UpdateSubscription(w http.ResponseWriter, r *http.Request, idStr string) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		http.Error(w, "Некорректный ID подписки", http.StatusBadRequest)
 		return
 	}
 
-	var input struct {
-		ServiceName string  `json:"service_name"`
-		Price       int     `json:"price"`
-		UserID      string  `json:"user_id"`
-		StartDate   string  `json:"start_date"`
-		EndDate     *string `json:"end_date,omitempty"`
-	}
-
+	var input api.UpdateSubscriptionRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		http.Error(w, "Некорректное тело запроса", http.StatusBadRequest)
 		return
 	}
 
-	if input.ServiceName == "" {
-		http.Error(w, "service_name не может быть пустым", http.StatusBadRequest)
-		return
-	}
-
-	if input.Price <= 0 {
-		http.Error(w, "price должен быть положительным целым числом", http.StatusBadRequest)
-		return
-	}
-
-	userID, err := uuid.Parse(input.UserID)
+	userID, err := uuid.Parse(input.UserId)
 	if err != nil {
 		http.Error(w, "user_id должен быть валидным UUID", http.StatusBadRequest)
 		return
 	}
 
-	if !isValidMonthYear(input.StartDate) {
-		http.Error(w, "start_date должен быть в формате MM-YYYY (например, 07-2025)", http.StatusBadRequest)
-		return
-	}
-
 	sub := &model.Subscription{
 		ID:          id,
 		ServiceName: input.ServiceName,
@@ -324,10 +341,7 @@ func (h *Handler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
 //	@Failure		404	{string}	string	"Subscription not found"
 //	@Failure		500	{string}	string	"Internal server error"
 //	@Router			/subscriptions/{id} [delete]
-func (h *Handler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	idStr := vars["id"]
-
+func (h *Handler) DeleteSubscription(w http.ResponseWriter, r *http.Request, idStr string) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		http.Error(w, "Некорректный ID подписки", http.StatusBadRequest)
@@ -343,21 +357,75 @@ func (h *Handler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.NotifierRepo.Enqueue(notifier.EventSubscriptionDeleted, notifier.Payload{
+		EventType:      notifier.EventSubscriptionDeleted,
+		SubscriptionID: id,
+		OccurredAt:     time.Now(),
+	}); err != nil {
+		log.Printf("Ошибка при постановке события удаления подписки в очередь: %v", err)
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func isValidMonthYear(s string) bool {
-	_, err := time.Parse("01-2006", s)
-	return err == nil
+// CreateCheckoutSession starts a Stripe Checkout Session for a subscription.
+//
+//	@Summary		Create a checkout session
+//	@Description	Create a Stripe Checkout Session for an existing subscription and return its URL
+//	@Tags			billing
+//	@Produce		json
+//	@Param			id	path		string	true	"Subscription ID"
+//	@Success		200	{object}	object{url=string}
+//	@Failure		400	{string}	string	"Invalid ID format"
+//	@Failure		404	{string}	string	"Subscription not found"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/subscriptions/{id}/checkout [post]
+func (h *Handler) CreateCheckoutSession(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	idStr := vars["id"]
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "Некорректный ID подписки", http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.SubscriptionRepo.GetByID(id)
+	if err != nil {
+		http.Error(w, "Подписка не найдена", http.StatusNotFound)
+		return
+	}
+
+	url, err := h.Billing.CreateCheckout(sub)
+	if err != nil {
+		http.Error(w, "Не удалось создать checkout-сессию", http.StatusInternalServerError)
+		return
+	}
+
+	SendJSON(w, http.StatusOK, map[string]string{"url": url})
 }
 
-// CreateSubscriptionRequest represents the request body for creating/updating a subscription.
+// StripeWebhook receives and applies Stripe subscription/invoice events.
 //
-//	@Description	Subscription creation request
-type CreateSubscriptionRequest struct {
-	ServiceName string  `json:"service_name" example:"Yandex Plus"`
-	Price       int     `json:"price" example:"400"`
-	UserID      string  `json:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
-	StartDate   string  `json:"start_date" example:"07-2025"`
-	EndDate     *string `json:"end_date,omitempty" example:"12-2025"`
+//	@Summary		Stripe webhook
+//	@Description	Verify and process a Stripe webhook event (invoice.paid, customer.subscription.updated, customer.subscription.deleted)
+//	@Tags			billing
+//	@Accept			json
+//	@Success		200	{string}	string
+//	@Failure		400	{string}	string	"Invalid payload or signature"
+//	@Router			/webhooks/stripe [post]
+func (h *Handler) StripeWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Не удалось прочитать тело запроса", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Billing.HandleWebhook(payload, r.Header.Get("Stripe-Signature")); err != nil {
+		log.Printf("Ошибка при обработке webhook Stripe: %v", err)
+		http.Error(w, "Не удалось обработать webhook", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
 }
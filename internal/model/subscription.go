@@ -11,4 +11,15 @@ type Subscription struct {
 	UserID      uuid.UUID `json:"user_id" db:"user_id"`
 	StartDate   string    `json:"start_date" db:"start_date"`
 	EndDate     *string   `json:"end_date,omitempty" db:"end_date"`
+
+	StripeCustomerID     *string `json:"stripe_customer_id,omitempty" db:"stripe_customer_id"`
+	StripeSubscriptionID *string `json:"stripe_subscription_id,omitempty" db:"stripe_subscription_id"`
+	Status               string  `json:"status" db:"status"`
 }
+
+// Subscription statuses as reported by the payment provider.
+const (
+	StatusActive   = "active"
+	StatusPastDue  = "past_due"
+	StatusCanceled = "canceled"
+)
@@ -0,0 +1,82 @@
+package notifier
+
+import (
+	"log"
+	"time"
+
+	"subscription-service/internal/repository"
+)
+
+// expiryOffset pairs a lookahead window with the event type fired for it.
+type expiryOffset struct {
+	days      int
+	eventType string
+}
+
+var expiryOffsets = []expiryOffset{
+	{days: 30, eventType: EventSubscriptionExpiringT30},
+	{days: 7, eventType: EventSubscriptionExpiringT7},
+	{days: 1, eventType: EventSubscriptionExpiringT1},
+}
+
+// ExpiryScanner enqueues notifier events for subscriptions approaching
+// their EndDate. Because model.Subscription.EndDate only carries MM-YYYY
+// precision, "T-30/T-7/T-1 days" is approximated as "EndDate falls in the
+// month that is 30/7/1 days from today" — so a subscription sits in the
+// same lookahead window for roughly a month of daily ticks. Repository.
+// EnqueueExpiryNotification dedups on (subscription, offset, month) so each
+// offset still only notifies once per subscription per month, despite the
+// daily tick.
+type ExpiryScanner struct {
+	subRepo      *repository.SubscriptionRepository
+	notifierRepo *Repository
+	interval     time.Duration
+}
+
+func NewExpiryScanner(subRepo *repository.SubscriptionRepository, notifierRepo *Repository) *ExpiryScanner {
+	return &ExpiryScanner{
+		subRepo:      subRepo,
+		notifierRepo: notifierRepo,
+		interval:     24 * time.Hour,
+	}
+}
+
+// Run blocks, scanning for approaching expiries once per interval until
+// stop is closed.
+func (s *ExpiryScanner) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.tick()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.tick()
+		}
+	}
+}
+
+func (s *ExpiryScanner) tick() {
+	now := time.Now()
+	for _, offset := range expiryOffsets {
+		monthYear := now.AddDate(0, 0, offset.days).Format("01-2006")
+
+		subs, err := s.subRepo.ListByEndDateMonth(monthYear)
+		if err != nil {
+			continue
+		}
+
+		for _, sub := range subs {
+			payload := Payload{
+				EventType:      offset.eventType,
+				SubscriptionID: sub.ID,
+				OccurredAt:     now,
+			}
+			if _, err := s.notifierRepo.EnqueueExpiryNotification(sub.ID, offset.eventType, monthYear, payload); err != nil {
+				log.Printf("Ошибка при постановке события %s для подписки %s: %v", offset.eventType, sub.ID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package notifier lets external clients register webhook subscribers that
+// receive signed callbacks about subscription lifecycle events (creation,
+// deletion, and upcoming expiry). Delivery is durable: events are written
+// to an outbox table and a background Scanner retries failed deliveries
+// with exponential backoff.
+package notifier
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types a subscriber can filter on.
+const (
+	EventSubscriptionCreated     = "subscription.created"
+	EventSubscriptionDeleted     = "subscription.deleted"
+	EventSubscriptionExpiringT30 = "subscription.expiring.t_minus_30"
+	EventSubscriptionExpiringT7  = "subscription.expiring.t_minus_7"
+	EventSubscriptionExpiringT1  = "subscription.expiring.t_minus_1"
+)
+
+// Subscriber is a registered webhook endpoint.
+type Subscriber struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	URL       string    `json:"url" db:"url"`
+	EventType string    `json:"event_type" db:"event_type"` // "*" matches every event
+	Secret    string    `json:"-" db:"secret"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// OutboxEvent is a durable, at-least-once delivery record. Repository
+// implementations persist it to Postgres so deliveries survive restarts.
+type OutboxEvent struct {
+	ID            uuid.UUID `db:"id"`
+	SubscriberID  uuid.UUID `db:"subscriber_id"`
+	EventType     string    `db:"event_type"`
+	Payload       []byte    `db:"payload"`
+	Attempts      int       `db:"attempts"`
+	NextAttemptAt time.Time `db:"next_attempt_at"`
+	Delivered     bool      `db:"delivered"`
+}
+
+// Payload is the JSON body delivered to a subscriber.
+type Payload struct {
+	EventType      string    `json:"event_type"`
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// maxAttempts bounds exponential backoff; after this many failed attempts
+// an outbox event is left undelivered for manual inspection.
+const maxAttempts = 8
+
+// backoff returns the delay before the next retry for the given attempt
+// count, doubling each time starting at 30s and capped at 1h.
+func backoff(attempts int) time.Duration {
+	delay := 30 * time.Second
+	for i := 0; i < attempts; i++ {
+		delay *= 2
+		if delay > time.Hour {
+			return time.Hour
+		}
+	}
+	return delay
+}
+
+// Sign computes the HMAC-SHA256 signature of body using secret, returned
+// as a lowercase hex string suitable for the X-GoEM-Signature header.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// marshalPayload is a small helper kept alongside Payload so callers don't
+// need to import encoding/json just to build an outbox row.
+func marshalPayload(p Payload) []byte {
+	data, err := json.Marshal(p)
+	if err != nil {
+		log.Printf("Ошибка при сериализации события нотификатора: %v", err)
+		return nil
+	}
+	return data
+}
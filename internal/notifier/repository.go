@@ -0,0 +1,212 @@
+package notifier
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Repository persists subscribers and their durable delivery outbox.
+type Repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateSubscriber(sub *Subscriber) error {
+	query := `
+		INSERT INTO notifier_subscribers (id, url, event_type, secret, created_at)
+		VALUES (:id, :url, :event_type, :secret, :created_at)
+	`
+	_, err := r.db.NamedExec(query, sub)
+	if err != nil {
+		log.Printf("Ошибка при регистрации подписчика нотификатора: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) ListSubscribers() ([]Subscriber, error) {
+	var subs []Subscriber
+	err := r.db.Select(&subs, "SELECT * FROM notifier_subscribers ORDER BY created_at DESC")
+	if err != nil {
+		log.Printf("Ошибка при получении списка подписчиков нотификатора: %v", err)
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *Repository) DeleteSubscriber(id uuid.UUID) error {
+	result, err := r.db.Exec("DELETE FROM notifier_subscribers WHERE id = $1", id)
+	if err != nil {
+		log.Printf("Ошибка при удалении подписчика нотификатора %s: %v", id, err)
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("подписчик с ID %s не найден", id)
+	}
+	return nil
+}
+
+// subscribersForEvent returns every subscriber whose EventType matches
+// eventType exactly or is the wildcard "*".
+func (r *Repository) subscribersForEvent(eventType string) ([]Subscriber, error) {
+	var subs []Subscriber
+	query := "SELECT * FROM notifier_subscribers WHERE event_type = $1 OR event_type = '*'"
+	if err := r.db.Select(&subs, query, eventType); err != nil {
+		log.Printf("Ошибка при выборке подписчиков для события %s: %v", eventType, err)
+		return nil, err
+	}
+	return subs, nil
+}
+
+// Enqueue fans eventType/payload out to every matching subscriber as a new
+// outbox row ready for immediate delivery.
+func (r *Repository) Enqueue(eventType string, payload Payload) error {
+	subs, err := r.subscribersForEvent(eventType)
+	if err != nil {
+		return err
+	}
+
+	body := marshalPayload(payload)
+	now := time.Now()
+	for _, sub := range subs {
+		event := OutboxEvent{
+			ID:            uuid.New(),
+			SubscriberID:  sub.ID,
+			EventType:     eventType,
+			Payload:       body,
+			Attempts:      0,
+			NextAttemptAt: now,
+			Delivered:     false,
+		}
+		query := `
+			INSERT INTO notifier_outbox (id, subscriber_id, event_type, payload, attempts, next_attempt_at, delivered)
+			VALUES (:id, :subscriber_id, :event_type, :payload, :attempts, :next_attempt_at, :delivered)
+		`
+		if _, err := r.db.NamedExec(query, event); err != nil {
+			log.Printf("Ошибка при постановке события %s в очередь доставки: %v", eventType, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// DueEvents returns undelivered outbox events whose next_attempt_at has
+// passed, ready for the Scanner to attempt delivery.
+func (r *Repository) DueEvents(limit int) ([]OutboxEvent, error) {
+	var events []OutboxEvent
+	query := `
+		SELECT * FROM notifier_outbox
+		WHERE delivered = false AND next_attempt_at <= now()
+		ORDER BY next_attempt_at
+		LIMIT $1
+	`
+	if err := r.db.Select(&events, query, limit); err != nil {
+		log.Printf("Ошибка при выборке событий к доставке: %v", err)
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *Repository) SubscriberByID(id uuid.UUID) (*Subscriber, error) {
+	var sub Subscriber
+	if err := r.db.Get(&sub, "SELECT * FROM notifier_subscribers WHERE id = $1", id); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// EnqueueExpiryNotification claims and enqueues an expiry notification for
+// subscriptionID in a single transaction. Claiming and enqueuing used to be
+// two separate calls, which meant a failed outbox insert after a
+// successful claim would be retried on no future tick (the claim row
+// alone already marked it as sent); doing both in one transaction means a
+// failed enqueue rolls the claim back too, so the next tick retries it.
+// Returns false if this (subscription, event_type, month_year) triple was
+// already claimed by an earlier tick.
+func (r *Repository) EnqueueExpiryNotification(subscriptionID uuid.UUID, eventType, monthYear string, payload Payload) (bool, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return false, fmt.Errorf("не удалось начать транзакцию: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT INTO notifier_expiry_sent (subscription_id, event_type, month_year, sent_at)
+		 VALUES ($1, $2, $3, now())
+		 ON CONFLICT (subscription_id, event_type, month_year) DO NOTHING`,
+		subscriptionID, eventType, monthYear,
+	)
+	if err != nil {
+		log.Printf("Ошибка при записи отметки отправки события %s для подписки %s: %v", eventType, subscriptionID, err)
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected == 0 {
+		return false, nil // already notified for this subscription/offset/month
+	}
+
+	var subs []Subscriber
+	if err := tx.Select(&subs, "SELECT * FROM notifier_subscribers WHERE event_type = $1 OR event_type = '*'", eventType); err != nil {
+		log.Printf("Ошибка при выборке подписчиков для события %s: %v", eventType, err)
+		return false, err
+	}
+
+	body := marshalPayload(payload)
+	now := time.Now()
+	for _, sub := range subs {
+		event := OutboxEvent{
+			ID:            uuid.New(),
+			SubscriberID:  sub.ID,
+			EventType:     eventType,
+			Payload:       body,
+			Attempts:      0,
+			NextAttemptAt: now,
+			Delivered:     false,
+		}
+		query := `
+			INSERT INTO notifier_outbox (id, subscriber_id, event_type, payload, attempts, next_attempt_at, delivered)
+			VALUES (:id, :subscriber_id, :event_type, :payload, :attempts, :next_attempt_at, :delivered)
+		`
+		if _, err := tx.NamedExec(query, event); err != nil {
+			log.Printf("Ошибка при постановке события %s в очередь доставки: %v", eventType, err)
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("не удалось зафиксировать транзакцию: %w", err)
+	}
+	return true, nil
+}
+
+// MarkDelivered flags an outbox event as successfully delivered.
+func (r *Repository) MarkDelivered(id uuid.UUID) error {
+	_, err := r.db.Exec("UPDATE notifier_outbox SET delivered = true WHERE id = $1", id)
+	return err
+}
+
+// ScheduleRetry bumps an outbox event's attempt count and schedules its
+// next_attempt_at using exponential backoff.
+func (r *Repository) ScheduleRetry(id uuid.UUID, attempts int) error {
+	next := time.Now().Add(backoff(attempts))
+	_, err := r.db.Exec(
+		"UPDATE notifier_outbox SET attempts = $1, next_attempt_at = $2 WHERE id = $3",
+		attempts, next, id,
+	)
+	return err
+}
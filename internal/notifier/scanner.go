@@ -0,0 +1,97 @@
+package notifier
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Scanner periodically polls the outbox for due events and delivers them,
+// rescheduling failed attempts with exponential backoff.
+type Scanner struct {
+	repo       *Repository
+	httpClient *http.Client
+	interval   time.Duration
+	batchSize  int
+}
+
+func NewScanner(repo *Repository) *Scanner {
+	return &Scanner{
+		repo:       repo,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		interval:   15 * time.Second,
+		batchSize:  100,
+	}
+}
+
+// Run blocks, polling for due events until stop is closed.
+func (s *Scanner) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.tick()
+		}
+	}
+}
+
+func (s *Scanner) tick() {
+	events, err := s.repo.DueEvents(s.batchSize)
+	if err != nil {
+		return
+	}
+
+	for _, event := range events {
+		s.deliver(event)
+	}
+}
+
+func (s *Scanner) deliver(event OutboxEvent) {
+	sub, err := s.repo.SubscriberByID(event.SubscriberID)
+	if err != nil {
+		log.Printf("Подписчик %s для события %s не найден, пропускаем", event.SubscriberID, event.ID)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, sub.URL, bytes.NewReader(event.Payload))
+	if err != nil {
+		s.fail(event)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GoEM-Signature", Sign(sub.Secret, event.Payload))
+	req.Header.Set("X-GoEM-Event", event.EventType)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		log.Printf("Ошибка доставки события %s подписчику %s: %v", event.ID, sub.ID, err)
+		s.fail(event)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("Подписчик %s ответил статусом %d на событие %s", sub.ID, resp.StatusCode, event.ID)
+		s.fail(event)
+		return
+	}
+
+	if err := s.repo.MarkDelivered(event.ID); err != nil {
+		log.Printf("Ошибка при пометке события %s доставленным: %v", event.ID, err)
+	}
+}
+
+func (s *Scanner) fail(event OutboxEvent) {
+	if event.Attempts+1 >= maxAttempts {
+		log.Printf("Событие %s исчерпало лимит попыток (%d), оставляем недоставленным", event.ID, maxAttempts)
+		return
+	}
+	if err := s.repo.ScheduleRetry(event.ID, event.Attempts+1); err != nil {
+		log.Printf("Ошибка при планировании повтора для события %s: %v", event.ID, err)
+	}
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"subscription-service/internal/model"
@@ -20,8 +21,8 @@ func NewSubscriptionRepository(db *sqlx.DB) *SubscriptionRepository {
 
 func (r *SubscriptionRepository) Create(sub *model.Subscription) error {
 	query := `
-		INSERT INTO subscriptions (id, service_name, price, user_id, start_date, end_date)
-		VALUES (:id, :service_name, :price, :user_id, :start_date, :end_date)
+		INSERT INTO subscriptions (id, service_name, price, user_id, start_date, end_date, stripe_customer_id, stripe_subscription_id, status)
+		VALUES (:id, :service_name, :price, :user_id, :start_date, :end_date, :stripe_customer_id, :stripe_subscription_id, :status)
 	`
 
 	_, err := r.db.NamedExec(query, sub)
@@ -44,7 +45,10 @@ func (r *SubscriptionRepository) GetByID(id uuid.UUID) (*model.Subscription, err
 	return &sub, nil
 }
 
-func (r *SubscriptionRepository) List(user_id *uuid.UUID, service_name *string) ([]model.Subscription, error) {
+// List returns subscriptions matching user_id/service_name, ordered by
+// start_date descending. limit/offset page the result; pass 0 for limit
+// to return every matching row.
+func (r *SubscriptionRepository) List(user_id *uuid.UUID, service_name *string, limit, offset int) ([]model.Subscription, error) {
 	query := "SELECT * FROM subscriptions WHERE 1=1"
 	args := []interface{}{}
 	argIndex := 1
@@ -58,10 +62,16 @@ func (r *SubscriptionRepository) List(user_id *uuid.UUID, service_name *string)
 	if service_name != nil {
 		query += fmt.Sprintf(" AND service_name = $%d", argIndex)
 		args = append(args, *service_name)
+		argIndex++
 	}
 
 	query += " ORDER BY start_date DESC"
 
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+		args = append(args, limit, offset)
+	}
+
 	var subs []model.Subscription
 	err := r.db.Select(&subs, query, args...)
 	if err != nil {
@@ -72,6 +82,40 @@ func (r *SubscriptionRepository) List(user_id *uuid.UUID, service_name *string)
 	return subs, nil
 }
 
+// Count returns how many subscriptions match user_id/service_name, with no
+// paging applied. Used alongside List to report X-Total-Count so a paginated
+// response doesn't silently hide rows beyond the current page.
+func (r *SubscriptionRepository) Count(user_id *uuid.UUID, service_name *string) (int, error) {
+	query := "SELECT COUNT(*) FROM subscriptions WHERE 1=1"
+	args := []interface{}{}
+	argIndex := 1
+
+	if user_id != nil {
+		query += fmt.Sprintf(" AND user_id = $%d", argIndex)
+		args = append(args, *user_id)
+		argIndex++
+	}
+
+	if service_name != nil {
+		query += fmt.Sprintf(" AND service_name = $%d", argIndex)
+		args = append(args, *service_name)
+		argIndex++
+	}
+
+	var count int
+	if err := r.db.Get(&count, query, args...); err != nil {
+		log.Printf("Ошибка при подсчёте подписок: %v", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetTotalCost preserves /subscriptions/total's original semantics: each
+// matching subscription's price is counted once, not once per month it
+// overlaps [startMonth, endMonth]. This is intentionally NOT built on top
+// of CostBreakdown, whose per-month totals count a subscription's price
+// again in every month it's active — summing those would silently inflate
+// GetTotalCost's result for any subscription spanning more than one month.
 func (r *SubscriptionRepository) GetTotalCost(startMonth, endMonth string, userID *uuid.UUID, serviceName *string) (int, error) {
 	if !isValidMonthYear(startMonth) || !isValidMonthYear(endMonth) {
 		return 0, fmt.Errorf("некорректный формат месяца: должен быть MM-YYYY")
@@ -98,15 +142,112 @@ func (r *SubscriptionRepository) GetTotalCost(startMonth, endMonth string, userI
 	}
 
 	var total int
-	err := r.db.Get(&total, query, args...)
-	if err != nil {
+	if err := r.db.Get(&total, query, args...); err != nil {
 		log.Printf("Ошибка при расчёте общей стоимости: %v", err)
 		return 0, err
 	}
-
 	return total, nil
 }
 
+// MonthCost is one month's worth of CostBreakdown output.
+type MonthCost struct {
+	Month     string         `json:"month" db:"month"`
+	Total     int            `json:"total" db:"total"`
+	ByService map[string]int `json:"by_service" db:"by_service"`
+	Partial   bool           `json:"partial,omitempty" db:"partial"`
+}
+
+// costBreakdownRow matches the SQL result shape; ByService comes back as
+// raw jsonb and is decoded into MonthCost.ByService.
+type costBreakdownRow struct {
+	Month     string `db:"month"`
+	Total     int    `db:"total"`
+	ByService []byte `db:"by_service"`
+	Boundary  bool   `db:"boundary"`
+}
+
+// CostBreakdown returns, for every month in [startMonth, endMonth], the
+// total cost and per-service breakdown of subscriptions active that
+// month. A subscription that starts or ends mid-window still counts for
+// the whole month it overlaps; when prorate is true, months where that
+// happened for at least one subscription are flagged Partial.
+func (r *SubscriptionRepository) CostBreakdown(startMonth, endMonth string, userID *uuid.UUID, serviceName *string, prorate bool) ([]MonthCost, error) {
+	if !isValidMonthYear(startMonth) || !isValidMonthYear(endMonth) {
+		return nil, fmt.Errorf("некорректный формат месяца: должен быть MM-YYYY")
+	}
+
+	query := `
+		WITH months AS (
+			SELECT generate_series(
+				to_date($1, 'MM-YYYY'),
+				to_date($2, 'MM-YYYY'),
+				interval '1 month'
+			)::date AS month
+		),
+		per_service AS (
+			SELECT
+				m.month,
+				s.service_name,
+				SUM(s.price) AS price,
+				bool_or(s.start_date = to_char(m.month, 'MM-YYYY') OR s.end_date = to_char(m.month, 'MM-YYYY')) AS boundary
+			FROM months m
+			JOIN subscriptions s
+				ON to_date(s.start_date, 'MM-YYYY') <= m.month
+				AND (s.end_date IS NULL OR to_date(s.end_date, 'MM-YYYY') >= m.month)
+	`
+	args := []interface{}{startMonth, endMonth}
+	argIndex := 3
+
+	if userID != nil {
+		query += fmt.Sprintf(" AND s.user_id = $%d", argIndex)
+		args = append(args, *userID)
+		argIndex++
+	}
+	if serviceName != nil {
+		query += fmt.Sprintf(" AND s.service_name = $%d", argIndex)
+		args = append(args, *serviceName)
+		argIndex++
+	}
+
+	query += `
+			GROUP BY m.month, s.service_name
+		)
+		SELECT
+			to_char(m.month, 'MM-YYYY') AS month,
+			COALESCE(SUM(p.price), 0) AS total,
+			COALESCE(jsonb_object_agg(p.service_name, p.price) FILTER (WHERE p.service_name IS NOT NULL), '{}') AS by_service,
+			COALESCE(bool_or(p.boundary), false) AS boundary
+		FROM months m
+		LEFT JOIN per_service p ON p.month = m.month
+		GROUP BY m.month
+		ORDER BY m.month
+	`
+
+	var rows []costBreakdownRow
+	if err := r.db.Select(&rows, query, args...); err != nil {
+		log.Printf("Ошибка при расчёте помесячной стоимости: %v", err)
+		return nil, err
+	}
+
+	months := make([]MonthCost, 0, len(rows))
+	for _, row := range rows {
+		var byService map[string]int
+		if err := json.Unmarshal(row.ByService, &byService); err != nil {
+			log.Printf("Ошибка при разборе by_service за %s: %v", row.Month, err)
+			byService = map[string]int{}
+		}
+
+		months = append(months, MonthCost{
+			Month:     row.Month,
+			Total:     row.Total,
+			ByService: byService,
+			Partial:   prorate && row.Boundary,
+		})
+	}
+
+	return months, nil
+}
+
 func isValidMonthYear(s string) bool {
 	_, err := time.Parse("01-2006", s)
 	return err == nil
@@ -140,6 +281,127 @@ func (r *SubscriptionRepository) Update(sub *model.Subscription) error {
 	return nil
 }
 
+// ListByEndDateMonth returns every subscription whose EndDate falls in the
+// given MM-YYYY month, used to find subscriptions approaching expiry.
+func (r *SubscriptionRepository) ListByEndDateMonth(monthYear string) ([]model.Subscription, error) {
+	if !isValidMonthYear(monthYear) {
+		return nil, fmt.Errorf("некорректный формат месяца: должен быть MM-YYYY")
+	}
+
+	var subs []model.Subscription
+	err := r.db.Select(&subs, "SELECT * FROM subscriptions WHERE end_date = $1", monthYear)
+	if err != nil {
+		log.Printf("Ошибка при получении подписок с окончанием в %s: %v", monthYear, err)
+		return nil, err
+	}
+	return subs, nil
+}
+
+// BulkImportError reports why a single row of a bulk import was rejected.
+type BulkImportError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// BulkCreate inserts every row of subs inside a single transaction. Each
+// row is first validated against isValidMonthYear; if any row is invalid
+// or fails to insert, the whole transaction is rolled back and every
+// collected error is returned so the caller can report a per-row result.
+func (r *SubscriptionRepository) BulkCreate(subs []model.Subscription) ([]BulkImportError, error) {
+	var importErrors []BulkImportError
+	for i, sub := range subs {
+		if !isValidMonthYear(sub.StartDate) {
+			importErrors = append(importErrors, BulkImportError{Row: i, Message: "start_date должен быть в формате MM-YYYY"})
+		}
+		if sub.EndDate != nil && !isValidMonthYear(*sub.EndDate) {
+			importErrors = append(importErrors, BulkImportError{Row: i, Message: "end_date должен быть в формате MM-YYYY"})
+		}
+	}
+	if len(importErrors) > 0 {
+		return importErrors, fmt.Errorf("импорт отклонён: найдены некорректные строки")
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("не удалось начать транзакцию: %w", err)
+	}
+
+	query := `
+		INSERT INTO subscriptions (id, service_name, price, user_id, start_date, end_date, stripe_customer_id, stripe_subscription_id, status)
+		VALUES (:id, :service_name, :price, :user_id, :start_date, :end_date, :stripe_customer_id, :stripe_subscription_id, :status)
+	`
+	for i, sub := range subs {
+		if _, err := tx.NamedExec(query, sub); err != nil {
+			tx.Rollback()
+			log.Printf("Ошибка при массовой вставке подписки (строка %d): %v", i, err)
+			return []BulkImportError{{Row: i, Message: err.Error()}}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("не удалось зафиксировать транзакцию: %w", err)
+	}
+	return nil, nil
+}
+
+// UpdateStatus sets the Status (and, when provided, the EndDate) of the
+// subscription identified by stripeSubscriptionID. It is used to apply
+// Stripe webhook events, so subscriptions are looked up by their Stripe
+// subscription ID rather than their local ID.
+func (r *SubscriptionRepository) UpdateStatus(stripeSubscriptionID, status string, endDate *string) error {
+	query := `
+		UPDATE subscriptions
+		SET status = $1,
+		    end_date = COALESCE($2, end_date)
+		WHERE stripe_subscription_id = $3
+	`
+
+	result, err := r.db.Exec(query, status, endDate, stripeSubscriptionID)
+	if err != nil {
+		log.Printf("Ошибка при обновлении статуса подписки (stripe_subscription_id=%s): %v", stripeSubscriptionID, err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("подписка с stripe_subscription_id %s не найдена", stripeSubscriptionID)
+	}
+
+	return nil
+}
+
+// UpdateStripeIDs attaches the Stripe customer/subscription IDs created by a
+// completed checkout session to the local subscription identified by id.
+// UpdateStatus looks subscriptions up by stripe_subscription_id, so this
+// must run before any webhook can apply a status change to the row.
+func (r *SubscriptionRepository) UpdateStripeIDs(id uuid.UUID, stripeCustomerID, stripeSubscriptionID string) error {
+	query := `
+		UPDATE subscriptions
+		SET stripe_customer_id = $1,
+		    stripe_subscription_id = $2
+		WHERE id = $3
+	`
+
+	result, err := r.db.Exec(query, stripeCustomerID, stripeSubscriptionID, id)
+	if err != nil {
+		log.Printf("Ошибка при сохранении Stripe ID для подписки %s: %v", id, err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("подписка с ID %s не найдена", id)
+	}
+
+	return nil
+}
+
 func (r *SubscriptionRepository) Delete(id uuid.UUID) error {
 	query := "DELETE FROM subscriptions WHERE id = $1"
 	result, err := r.db.Exec(query, id)